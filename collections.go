@@ -0,0 +1,109 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package envconf
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// decodeMap populates a map field by discovering its keys from env
+// entries sharing the prefix name+sep: the path segment immediately
+// following that prefix becomes a (lower-cased) map key, and the
+// remainder, if any, is decoded as that key's value via decodeField, so
+// map values may themselves be literals or structs.
+func (env Environment) decodeMap(name, sep string, value reflect.Value, state *decodeState) error {
+	typ := value.Type()
+	if typ.Key().Kind() != reflect.String {
+		return fmt.Errorf("Unsupported map key type %s", typ.Key())
+	}
+	keys := env.discoverKeys(name, sep)
+	if len(keys) == 0 {
+		return nil
+	}
+	result := reflect.MakeMap(typ)
+	for _, key := range keys {
+		element := reflect.New(typ.Elem()).Elem()
+		if err := env.decodeField(name+sep+key, sep, element, envTag{}, state); err != nil {
+			return err
+		}
+		result.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), element)
+	}
+	value.Set(result)
+	return nil
+}
+
+// discoverKeys returns the distinct, lower-cased path segments that
+// immediately follow prefix+sep among env's keys, in sorted order.
+func (env Environment) discoverKeys(prefix, sep string) []string {
+	base := prefix + sep
+	seen := make(map[string]bool)
+	var keys []string
+	for key := range env {
+		if !hasPrefixFold(key, base) {
+			continue
+		}
+		rest := key[len(base):]
+		if idx := strings.Index(rest, sep); idx >= 0 {
+			rest = rest[:idx]
+		}
+		rest = strings.ToLower(rest)
+		if rest == "" || seen[rest] {
+			continue
+		}
+		seen[rest] = true
+		keys = append(keys, rest)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decodeStructSlice populates a []Struct field by decoding elements at
+// numeric indices name+sep+"0", name+sep+"1", ... for as many indices as
+// are contiguously present in env, starting from 0.
+func (env Environment) decodeStructSlice(name, sep string, value reflect.Value, state *decodeState) error {
+	typ := value.Type()
+	count := env.discoverIndexCount(name, sep)
+	if count == 0 {
+		return nil
+	}
+	result := reflect.MakeSlice(typ, count, count)
+	for i := 0; i < count; i++ {
+		elemName := name + sep + strconv.Itoa(i)
+		if err := env.decodeField(elemName, sep, result.Index(i), envTag{}, state); err != nil {
+			return err
+		}
+	}
+	value.Set(result)
+	return nil
+}
+
+// discoverIndexCount returns the number of contiguous numeric indices
+// 0, 1, 2, ... present as the path segment following prefix+sep among
+// env's keys.
+func (env Environment) discoverIndexCount(prefix, sep string) int {
+	indices := make(map[int]bool)
+	base := prefix + sep
+	for key := range env {
+		if !hasPrefixFold(key, base) {
+			continue
+		}
+		rest := key[len(base):]
+		if idx := strings.Index(rest, sep); idx >= 0 {
+			rest = rest[:idx]
+		}
+		if index, err := strconv.Atoi(rest); err == nil && index >= 0 {
+			indices[index] = true
+		}
+	}
+	count := 0
+	for indices[count] {
+		count++
+	}
+	return count
+}
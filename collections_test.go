@@ -0,0 +1,104 @@
+package envconf
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type upstream struct {
+	Host string
+	Port int
+}
+
+type collectionsConfig struct {
+	Labels    map[string]string
+	Listeners map[string]upstream
+	Upstreams []upstream
+}
+
+func TestDecodeMap(t *testing.T) {
+	env := New([]string{
+		"APP_LABELS_ENV=prod",
+		"APP_LABELS_REGION=us-east",
+	})
+	var cfg collectionsConfig
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	expected := map[string]string{"env": "prod", "region": "us-east"}
+	if !reflect.DeepEqual(cfg.Labels, expected) {
+		t.Errorf("Labels = %#v; want %#v", cfg.Labels, expected)
+	}
+}
+
+func TestDecodeMapOfStruct(t *testing.T) {
+	env := New([]string{
+		"APP_LISTENERS_PUBLIC_HOST=0.0.0.0",
+		"APP_LISTENERS_PUBLIC_PORT=443",
+		"APP_LISTENERS_ADMIN_HOST=127.0.0.1",
+		"APP_LISTENERS_ADMIN_PORT=8081",
+	})
+	var cfg collectionsConfig
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	expected := map[string]upstream{
+		"public": {Host: "0.0.0.0", Port: 443},
+		"admin":  {Host: "127.0.0.1", Port: 8081},
+	}
+	if !reflect.DeepEqual(cfg.Listeners, expected) {
+		t.Errorf("Listeners = %#v; want %#v", cfg.Listeners, expected)
+	}
+}
+
+func TestDecodeStructSlice(t *testing.T) {
+	env := New([]string{
+		"APP_UPSTREAMS_0_HOST=a.internal",
+		"APP_UPSTREAMS_0_PORT=9000",
+		"APP_UPSTREAMS_1_HOST=b.internal",
+		"APP_UPSTREAMS_1_PORT=9001",
+	})
+	var cfg collectionsConfig
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	expected := []upstream{
+		{Host: "a.internal", Port: 9000},
+		{Host: "b.internal", Port: 9001},
+	}
+	if !reflect.DeepEqual(cfg.Upstreams, expected) {
+		t.Errorf("Upstreams = %#v; want %#v", cfg.Upstreams, expected)
+	}
+}
+
+func TestDecodeSliceOfLiteralStruct(t *testing.T) {
+	env := New([]string{
+		"APP_EXPIRATIONS=2024-01-02T15:04:05Z,2025-01-02T15:04:05Z",
+	})
+	var cfg struct {
+		Expirations []time.Time
+	}
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	expected := []time.Time{
+		time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(cfg.Expirations, expected) {
+		t.Errorf("Expirations = %#v; want %#v", cfg.Expirations, expected)
+	}
+}
+
+func TestDecodeStrictWithCollections(t *testing.T) {
+	env := New([]string{
+		"APP_LABELS_ENV=prod",
+		"APP_UPSTREAMS_0_HOST=a.internal",
+		"APP_UPSTREAMS_0_PORT=9000",
+	})
+	var cfg collectionsConfig
+	if err := env.DecodeStrict("APP", "_", &cfg, nil); err != nil {
+		t.Errorf("Unexpected error decoding environment: %s", err)
+	}
+}
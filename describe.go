@@ -0,0 +1,160 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package envconf
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"text/tabwriter"
+)
+
+// FieldDoc documents a single environment variable declared by a config
+// struct, as discovered by Describe.
+type FieldDoc struct {
+	// Name is the fully-prefixed environment variable name, e.g. "APP_PORT".
+	Name string
+	// Type is the field's Go type, formatted as a string (e.g. "int",
+	// "time.Duration").
+	Type string
+	// Default is the value from a default= tag, and HasDefault reports
+	// whether one was present.
+	Default    string
+	HasDefault bool
+	// Required reports whether the field is tagged env:"...,required".
+	Required bool
+	// Description comes from the field's desc struct tag, if any.
+	Description string
+}
+
+// Describe walks v, which must be a struct or a pointer to one, the same
+// way Decode does, returning one FieldDoc per environment variable it
+// declares. It only inspects v's type, not its contents, so v need not be
+// populated; a zero value is enough.
+//
+// Map and []struct fields have no fixed set of env keys to report, since
+// those are discovered from data decodeMap and decodeStructSlice don't
+// have here; Describe documents them with a literal "*" (for map keys)
+// or "N" (for slice indices) placeholder in their place.
+func Describe(v interface{}, prefix, sep string) ([]FieldDoc, error) {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("envconf: Describe requires a struct or pointer to struct, got %T", v)
+	}
+	var docs []FieldDoc
+	describeType(prefix, sep, typ, &docs)
+	return docs, nil
+}
+
+// describeType is the type-only counterpart of decodeField: it walks
+// typ's fields, recursing into nested structs and emitting a FieldDoc for
+// every field decodeField would eventually treat as a literal.
+func describeType(name, sep string, typ reflect.Type, docs *[]FieldDoc) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := parseEnvTag(field)
+		if tag.skip {
+			continue
+		}
+		fieldName := name + sep + tag.name
+		if field.Anonymous && field.Tag.Get("env") == "" {
+			fieldName = name
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case fieldType.Kind() == reflect.Map:
+			describeElem(fieldName+sep+"*", sep, fieldType.Elem(), field, tag, docs)
+		case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Struct && !isLiteralTypeType(fieldType.Elem()):
+			describeType(fieldName+sep+"N", sep, fieldType.Elem(), docs)
+		case fieldType.Kind() == reflect.Struct && !isLiteralTypeType(fieldType):
+			describeType(fieldName, sep, fieldType, docs)
+		default:
+			*docs = append(*docs, fieldDocFor(fieldName, fieldType, field, tag))
+		}
+	}
+}
+
+// describeElem documents a map field's element type at name, recursing
+// if the element is itself a struct worth walking field by field.
+func describeElem(name, sep string, elemType reflect.Type, field reflect.StructField, tag envTag, docs *[]FieldDoc) {
+	if elemType.Kind() == reflect.Struct && !isLiteralTypeType(elemType) {
+		describeType(name, sep, elemType, docs)
+		return
+	}
+	*docs = append(*docs, fieldDocFor(name, elemType, field, tag))
+}
+
+// fieldDocFor builds the FieldDoc for a single literal-typed field.
+func fieldDocFor(name string, typ reflect.Type, field reflect.StructField, tag envTag) FieldDoc {
+	return FieldDoc{
+		Name:        name,
+		Type:        typ.String(),
+		Default:     tag.defaultValue,
+		HasDefault:  tag.hasDefault,
+		Required:    tag.required,
+		Description: field.Tag.Get("desc"),
+	}
+}
+
+// PrintUsage writes a table of every environment variable v declares to
+// w, one row per FieldDoc returned by Describe. When markdown is true,
+// the table is rendered as a Markdown table suitable for pasting into
+// documentation; otherwise it is aligned with a tabwriter for terminal
+// output.
+func PrintUsage(w io.Writer, v interface{}, prefix, sep string, markdown bool) error {
+	docs, err := Describe(v, prefix, sep)
+	if err != nil {
+		return err
+	}
+	if markdown {
+		return printUsageMarkdown(w, docs)
+	}
+	return printUsageTable(w, docs)
+}
+
+func printUsageTable(w io.Writer, docs []FieldDoc) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+	for _, doc := range docs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", doc.Name, doc.Type, defaultColumn(doc), requiredColumn(doc), doc.Description)
+	}
+	return tw.Flush()
+}
+
+func printUsageMarkdown(w io.Writer, docs []FieldDoc) error {
+	if _, err := io.WriteString(w, "| Name | Type | Default | Required | Description |\n|---|---|---|---|---|\n"); err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		line := fmt.Sprintf("| `%s` | %s | %s | %s | %s |\n", doc.Name, doc.Type, defaultColumn(doc), requiredColumn(doc), doc.Description)
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func defaultColumn(doc FieldDoc) string {
+	if !doc.HasDefault {
+		return "-"
+	}
+	return doc.Default
+}
+
+func requiredColumn(doc FieldDoc) string {
+	if doc.Required {
+		return "yes"
+	}
+	return "no"
+}
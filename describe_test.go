@@ -0,0 +1,78 @@
+package envconf
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type describedUpstream struct {
+	Host string `env:"host" desc:"Upstream hostname"`
+}
+
+type describedConfig struct {
+	Port     int                          `env:"port,default=8080" desc:"Listen port"`
+	Name     string                       `env:"name,required" desc:"Service name"`
+	Timeout  time.Duration                `env:"timeout,default=5s"`
+	Upstream map[string]describedUpstream `env:"upstream"`
+}
+
+func TestDescribe(t *testing.T) {
+	docs, err := Describe(&describedConfig{}, "APP", "_")
+	if err != nil {
+		t.Fatalf("Describe: %s", err)
+	}
+
+	byName := make(map[string]FieldDoc)
+	for _, doc := range docs {
+		byName[doc.Name] = doc
+	}
+
+	port, ok := byName["APP_port"]
+	if !ok {
+		t.Fatalf("missing doc for APP_port, got %+v", docs)
+	}
+	if port.Type != "int" || !port.HasDefault || port.Default != "8080" || port.Description != "Listen port" {
+		t.Errorf("APP_port doc = %+v", port)
+	}
+
+	name, ok := byName["APP_name"]
+	if !ok || !name.Required {
+		t.Errorf("APP_name doc = %+v; want Required", name)
+	}
+
+	if _, ok := byName["APP_upstream_*_host"]; !ok {
+		t.Errorf("missing doc for discovered map field, got %+v", docs)
+	}
+}
+
+func TestDescribeNonStruct(t *testing.T) {
+	if _, err := Describe(42, "APP", "_"); err == nil {
+		t.Error("expected error describing a non-struct value, got nil")
+	}
+}
+
+func TestPrintUsageTable(t *testing.T) {
+	var buf strings.Builder
+	if err := PrintUsage(&buf, &describedConfig{}, "APP", "_", false); err != nil {
+		t.Fatalf("PrintUsage: %s", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "NAME") || !strings.Contains(out, "APP_port") {
+		t.Errorf("table output missing expected content:\n%s", out)
+	}
+}
+
+func TestPrintUsageMarkdown(t *testing.T) {
+	var buf strings.Builder
+	if err := PrintUsage(&buf, &describedConfig{}, "APP", "_", true); err != nil {
+		t.Fatalf("PrintUsage: %s", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "| Name | Type | Default | Required | Description |\n") {
+		t.Errorf("markdown output missing header:\n%s", out)
+	}
+	if !strings.Contains(out, "`APP_port`") {
+		t.Errorf("markdown output missing APP_port row:\n%s", out)
+	}
+}
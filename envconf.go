@@ -6,6 +6,7 @@
 package envconf
 
 import (
+	"encoding"
 	"fmt"
 	"os"
 	"reflect"
@@ -84,51 +85,155 @@ getEnv:
 	return nil
 }
 
-// decode decodes env into v.
+// decode decodes env into v. If v has any fields tagged env:"...,required"
+// whose variables are unset, decode still decodes everything else and
+// returns their combined ValidationErrors once the whole tree is walked.
 func (env Environment) decode(prefix, sep string, v interface{}, fields *[]string) error {
 	value := reflect.ValueOf(v)
 	if value.Kind() != reflect.Ptr || value.IsNil() {
-		return fmt.Errorf("Non-pointer type '%s'", value.Type())
+		return fmt.Errorf("Non-pointer type '%T'", v)
+	}
+	state := &decodeState{trackFields: fields != nil}
+	if err := env.decodeField(prefix, sep, indirect(value), envTag{}, state); err != nil {
+		return err
+	}
+	if fields != nil {
+		*fields = state.fields
+	}
+	if len(state.required) > 0 {
+		return state.required
+	}
+	return nil
+}
+
+// decodeState threads bookkeeping through a decode pass that individual
+// decodeField calls can't return directly without aborting the walk: the
+// env keys successfully consumed (for DecodeStrict) and any required
+// fields found missing (aggregated rather than failing fast).
+type decodeState struct {
+	trackFields bool
+	fields      []string
+	required    ValidationErrors
+}
+
+func (s *decodeState) addField(name string) {
+	if s.trackFields {
+		s.fields = append(s.fields, name)
 	}
-	return env.decodeField(prefix, sep, indirect(value), fields)
+}
+
+func (s *decodeState) addRequired(err error) {
+	s.required = append(s.required, err)
 }
 
 // decodeField decodes an environment variable into a struct field. Literals
-// are decoded directly into the value; structs are decoded recursively.
-func (env Environment) decodeField(name, sep string, value reflect.Value, fields *[]string) error {
+// are decoded directly into the value; structs are decoded recursively,
+// except for struct types that are decoded as literals themselves (see
+// isLiteralType). tag carries the env struct tag options (default, required,
+// expand, file) that apply when value turns out to be a literal; it is the
+// zero envTag for the root value and for map/slice elements, which have no
+// struct field of their own to carry a tag.
+func (env Environment) decodeField(name, sep string, value reflect.Value, tag envTag, state *decodeState) error {
+	value = indirect(value)
 	typ := value.Type()
-	if typ.Kind() != reflect.Struct {
+	if typ.Kind() == reflect.Map {
+		return env.decodeMap(name, sep, value, state)
+	}
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Struct && !isLiteralTypeType(typ.Elem()) {
+		return env.decodeStructSlice(name, sep, value, state)
+	}
+	if typ.Kind() != reflect.Struct || isLiteralType(value) {
 		source, ok := env.Get(name)
+		if !ok && tag.hasDefault {
+			source, ok = tag.defaultValue, true
+		}
 		if !ok {
+			if tag.required {
+				state.addRequired(&RequiredFieldError{Field: name})
+			}
 			return nil
 		}
-		if err := decodeLiteral(source, value); err != nil {
-			return nil
+		if tag.file {
+			data, err := os.ReadFile(source)
+			if err != nil {
+				return fmt.Errorf("envconf: reading file for '%s': %w", name, err)
+			}
+			source = strings.TrimSpace(string(data))
+		}
+		if tag.expand {
+			source = env.expand(source)
 		}
-		if fields != nil {
-			*fields = append(*fields, name)
+		if err := decodeLiteral(source, value); err != nil {
+			return err
 		}
+		state.addField(name)
 		return nil
 	}
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
-		tag := field.Tag.Get("env")
-		if tag == "-" {
+		fieldTag := parseEnvTag(field)
+		if fieldTag.skip {
 			continue
 		}
-		if len(tag) == 0 {
-			tag = field.Name
+		fieldName := name + sep + fieldTag.name
+		if field.PkgPath != "" {
+			// Unexported fields cannot be set via reflection; skip them, as
+			// encoding/json and similar packages do, but still register the
+			// env key they would have occupied so DecodeStrict doesn't flag
+			// it as unrecognized.
+			state.addField(fieldName)
+			continue
 		}
-		if err := env.decodeField(name+sep+tag, sep, value.Field(i), fields); err != nil {
+		if field.Anonymous && field.Tag.Get("env") == "" {
+			// Anonymous fields without an explicit tag are promoted: their
+			// own fields are decoded directly under name, with no added
+			// path segment, mirroring how encoding/json treats embedding.
+			fieldName = name
+		}
+		if err := env.decodeField(fieldName, sep, value.Field(i), fieldTag, state); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// decodeLiteral decodes a source string into a value. Only integers, floats,
-// Booleans, slices, and strings are supported.
+// expand performs ${VAR} substitution on source, looking variables up in
+// env first and falling back to the process environment, the same
+// convention DotEnvProvider uses.
+func (env Environment) expand(source string) string {
+	return os.Expand(source, func(name string) string {
+		if value, ok := env.Get(name); ok {
+			return value
+		}
+		return os.Getenv(name)
+	})
+}
+
+// envFieldName returns the env tag name for field, falling back to the Go
+// field name, and reports whether the field participates in env decoding
+// at all (fields tagged env:"-" do not).
+func envFieldName(field reflect.StructField) (name string, ok bool) {
+	tag := parseEnvTag(field)
+	return tag.name, !tag.skip
+}
+
+// decodeLiteral decodes a source string into value. Besides integers,
+// floats, Booleans, slices, and strings, decodeLiteral recognizes the
+// Unmarshaler and encoding.TextUnmarshaler interfaces and a handful of
+// standard library types; see literal_types.go.
 func decodeLiteral(source string, value reflect.Value) error {
+	if value.CanAddr() {
+		addr := value.Addr().Interface()
+		if u, ok := addr.(Unmarshaler); ok {
+			return u.UnmarshalEnv(source)
+		}
+		if u, ok := addr.(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(source))
+		}
+	}
+	if decode, ok := literalTypeDecoders[value.Type()]; ok {
+		return decode(source, value)
+	}
 	kind := value.Type().Kind()
 	if kind >= reflect.Int && kind <= reflect.Int64 {
 		result, err := strconv.ParseInt(source, 0, value.Type().Bits())
@@ -173,40 +278,30 @@ func decodeLiteral(source string, value reflect.Value) error {
 	return fmt.Errorf("Unsupported type %s", kind)
 }
 
-// splitList splits a comma-separated list into a slice of strings, accounting
-// for escape characters.
+// splitList splits a comma-separated list into a slice of strings. A
+// backslash escapes the character that follows it, so "a\,b" is a single
+// element "a,b" and "a\\b" is a single element "a\b".
 func splitList(source string) (results []string) {
-	var (
-		isEscaped, hasEscape bool
-		lastIndex, index     int
-	)
-	for ; index < len(source); index++ {
+	var current strings.Builder
+	var isEscaped bool
+	for i := 0; i < len(source); i++ {
+		c := source[i]
 		if isEscaped {
+			current.WriteByte(c)
 			isEscaped = false
 			continue
 		}
-		switch source[index] {
+		switch c {
 		case '\\':
 			isEscaped = true
-			hasEscape = true
-
 		case ',':
-			result := source[lastIndex:index]
-			if hasEscape {
-				result = strings.Map(removeEscape, result)
-				hasEscape = false
-			}
-			results = append(results, result)
-			lastIndex = index + 1
+			results = append(results, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
 		}
 	}
-	if lastIndex < index {
-		result := source[lastIndex:]
-		if hasEscape {
-			result = strings.Map(removeEscape, result)
-		}
-		results = append(results, result)
-	}
+	results = append(results, current.String())
 	return results
 }
 
@@ -226,10 +321,14 @@ func decodeSlice(source string, value reflect.Value) error {
 }
 
 // indirect returns the value pointed to by a pointer, allocating zero values
-// for nil pointers.
+// for nil pointers. Nil pointers obtained from unexported fields cannot be
+// allocated into; indirect leaves those untouched and returns them as-is.
 func indirect(value reflect.Value) reflect.Value {
 	for value.Kind() == reflect.Ptr {
 		if value.IsNil() {
+			if !value.CanSet() {
+				return value
+			}
 			value.Set(reflect.New(value.Type().Elem()))
 		}
 		value = reflect.Indirect(value)
@@ -241,11 +340,3 @@ func indirect(value reflect.Value) reflect.Value {
 func hasPrefixFold(s, prefix string) bool {
 	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
 }
-
-// removeEscape is used by splitList to remove escape characters.
-func removeEscape(r rune) rune {
-	if r == '\\' {
-		return -1
-	}
-	return r
-}
\ No newline at end of file
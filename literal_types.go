@@ -0,0 +1,126 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package envconf
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// Unmarshaler is implemented by types that know how to decode their own
+// value from a single environment variable. It is checked before
+// encoding.TextUnmarshaler and before decodeLiteral's built-in type
+// support, so it always takes precedence.
+type Unmarshaler interface {
+	UnmarshalEnv(source string) error
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	bytesType    = reflect.TypeOf([]byte(nil))
+	ipType       = reflect.TypeOf(net.IP(nil))
+	ipNetType    = reflect.TypeOf(net.IPNet{})
+	urlType      = reflect.TypeOf(url.URL{})
+)
+
+// literalTypeDecoders maps standard library types decodeLiteral gives
+// dedicated support to their decode functions. Struct types listed here
+// (time.Time, net.IPNet, url.URL) are decoded as literals rather than recursed into
+// as ordinary structs; see isLiteralType.
+var literalTypeDecoders = map[reflect.Type]func(string, reflect.Value) error{
+	durationType: func(source string, value reflect.Value) error {
+		result, err := time.ParseDuration(source)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(result))
+		return nil
+	},
+	timeType: func(source string, value reflect.Value) error {
+		result, err := time.Parse(time.RFC3339, source)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(result))
+		return nil
+	},
+	bytesType: func(source string, value reflect.Value) error {
+		result, err := base64.StdEncoding.DecodeString(source)
+		if err != nil {
+			return err
+		}
+		value.SetBytes(result)
+		return nil
+	},
+	ipType: func(source string, value reflect.Value) error {
+		ip := net.ParseIP(source)
+		if ip == nil {
+			return fmt.Errorf("Invalid IP address '%s'", source)
+		}
+		value.Set(reflect.ValueOf(ip))
+		return nil
+	},
+	ipNetType: func(source string, value reflect.Value) error {
+		_, ipNet, err := net.ParseCIDR(source)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(*ipNet))
+		return nil
+	},
+	urlType: func(source string, value reflect.Value) error {
+		result, err := url.Parse(source)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(*result))
+		return nil
+	},
+}
+
+// isLiteralType reports whether value's type is a struct that
+// decodeField should decode as a single literal (via decodeLiteral)
+// rather than recurse into field by field: either one of the standard
+// library types decodeLiteral has dedicated support for, or a type
+// implementing Unmarshaler or encoding.TextUnmarshaler.
+func isLiteralType(value reflect.Value) bool {
+	if !value.CanAddr() {
+		return isLiteralTypeType(value.Type())
+	}
+	addr := value.Addr().Interface()
+	if _, ok := addr.(Unmarshaler); ok {
+		return true
+	}
+	if _, ok := addr.(encoding.TextUnmarshaler); ok {
+		return true
+	}
+	return isLiteralTypeType(value.Type())
+}
+
+// isLiteralTypeType is the reflect.Type-only half of isLiteralType, for
+// callers such as Describe that have a field's declared type but no
+// addressable value to check for Unmarshaler/TextUnmarshaler: it checks
+// the method sets of typ's pointer type instead.
+func isLiteralTypeType(typ reflect.Type) bool {
+	if _, ok := literalTypeDecoders[typ]; ok {
+		return true
+	}
+	ptr := reflect.PtrTo(typ)
+	if ptr.Implements(unmarshalerType) || ptr.Implements(textUnmarshalerType) {
+		return true
+	}
+	return false
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
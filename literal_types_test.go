@@ -0,0 +1,69 @@
+package envconf
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// upperCase implements Unmarshaler, always storing its input prefixed with
+// "UPPER:".
+type upperCase string
+
+func (u *upperCase) UnmarshalEnv(source string) error {
+	*u = upperCase(fmt.Sprintf("UPPER:%s", source))
+	return nil
+}
+
+// csv implements encoding.TextUnmarshaler.
+type csv []string
+
+func (c *csv) UnmarshalText(text []byte) error {
+	*c = csv{"text:" + string(text)}
+	return nil
+}
+
+type literalTypesConfig struct {
+	Expires  time.Time
+	Endpoint url.URL `env:"endpoint"`
+	Host     net.IP  `env:"host"`
+	Subnet   net.IPNet
+	Custom   upperCase
+	Text     csv
+}
+
+func TestDecodeLiteralTypes(t *testing.T) {
+	env := New([]string{
+		"APP_EXPIRES=2024-01-02T15:04:05Z",
+		"APP_ENDPOINT=https://example.com/path",
+		"APP_HOST=192.0.2.1",
+		"APP_SUBNET=192.0.2.0/24",
+		"APP_CUSTOM=hello",
+		"APP_TEXT=hello",
+	})
+	var cfg literalTypesConfig
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	wantExpires, _ := time.Parse(time.RFC3339, "2024-01-02T15:04:05Z")
+	if !cfg.Expires.Equal(wantExpires) {
+		t.Errorf("Expires = %v; want %v", cfg.Expires, wantExpires)
+	}
+	if cfg.Endpoint.String() != "https://example.com/path" {
+		t.Errorf("Endpoint = %v; want https://example.com/path", cfg.Endpoint.String())
+	}
+	if !cfg.Host.Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("Host = %v; want 192.0.2.1", cfg.Host)
+	}
+	if cfg.Subnet.String() != "192.0.2.0/24" {
+		t.Errorf("Subnet = %v; want 192.0.2.0/24", cfg.Subnet.String())
+	}
+	if cfg.Custom != "UPPER:hello" {
+		t.Errorf("Custom = %v; want UPPER:hello", cfg.Custom)
+	}
+	if len(cfg.Text) != 1 || cfg.Text[0] != "text:hello" {
+		t.Errorf("Text = %v; want [text:hello]", cfg.Text)
+	}
+}
@@ -0,0 +1,203 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package envconf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML parses the practical subset of YAML documented on
+// YAMLProvider: nested mappings via two-space indentation, block and
+// flow sequences, and plain/quoted scalars.
+func parseYAML(source string) (map[string]interface{}, error) {
+	lines := yamlLines(source)
+	root := make(map[string]interface{})
+	_, err := parseYAMLBlock(lines, 0, 0, root)
+	return root, err
+}
+
+// yamlLine is a single non-blank, non-comment line of YAML with its
+// indentation measured in spaces.
+type yamlLine struct {
+	indent int
+	text   string
+}
+
+func yamlLines(source string) []yamlLine {
+	var lines []yamlLine
+	for _, raw := range strings.Split(source, "\n") {
+		trimmed := strings.TrimRight(raw, " \t\r")
+		stripped := strings.TrimLeft(trimmed, " ")
+		if stripped == "" || strings.HasPrefix(stripped, "#") || stripped == "---" {
+			continue
+		}
+		lines = append(lines, yamlLine{indent: len(trimmed) - len(stripped), text: stripped})
+	}
+	return lines
+}
+
+// parseYAMLBlock consumes lines starting at index that belong to the
+// mapping at indent, writing key/value pairs into out. It returns the
+// index of the first line not belonging to this block.
+func parseYAMLBlock(lines []yamlLine, index, indent int, out map[string]interface{}) (int, error) {
+	for index < len(lines) {
+		line := lines[index]
+		if line.indent < indent {
+			return index, nil
+		}
+		if line.indent > indent {
+			return index, fmt.Errorf("envconf: unexpected indentation at %q", line.text)
+		}
+		colon := yamlKeySplit(line.text)
+		if colon < 0 {
+			return index, fmt.Errorf("envconf: expected 'key: value', got %q", line.text)
+		}
+		key := strings.TrimSpace(line.text[:colon])
+		rest := strings.TrimSpace(line.text[colon+1:])
+		index++
+		if rest == "" {
+			if index < len(lines) && lines[index].indent > indent && strings.HasPrefix(lines[index].text, "- ") {
+				var seq []interface{}
+				seq, index = parseYAMLSeq(lines, index, lines[index].indent)
+				out[key] = seq
+				continue
+			}
+			nested := make(map[string]interface{})
+			if index < len(lines) && lines[index].indent > indent {
+				index, _ = parseYAMLBlock(lines, index, lines[index].indent, nested)
+			}
+			out[key] = nested
+			continue
+		}
+		out[key] = yamlScalar(rest)
+	}
+	return index, nil
+}
+
+func parseYAMLSeq(lines []yamlLine, index, indent int) ([]interface{}, int) {
+	var seq []interface{}
+	for index < len(lines) && lines[index].indent == indent && strings.HasPrefix(lines[index].text, "- ") {
+		seq = append(seq, yamlScalar(strings.TrimSpace(lines[index].text[2:])))
+		index++
+	}
+	return seq, index
+}
+
+// yamlKeySplit finds the colon that separates a mapping key from its
+// value, ignoring colons inside quoted strings.
+func yamlKeySplit(text string) int {
+	inQuote := byte(0)
+	for i := 0; i < len(text); i++ {
+		switch {
+		case inQuote != 0:
+			if text[i] == inQuote {
+				inQuote = 0
+			}
+		case text[i] == '\'' || text[i] == '"':
+			inQuote = text[i]
+		case text[i] == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+// yamlSplitCommas splits a flow sequence's inner text on top-level commas,
+// the same way yamlKeySplit locates a top-level colon: commas inside a
+// quoted string are not split points.
+func yamlSplitCommas(text string) []string {
+	var parts []string
+	inQuote := byte(0)
+	last := 0
+	for i := 0; i < len(text); i++ {
+		switch {
+		case inQuote != 0:
+			if text[i] == inQuote {
+				inQuote = 0
+			}
+		case text[i] == '\'' || text[i] == '"':
+			inQuote = text[i]
+		case text[i] == ',':
+			parts = append(parts, text[last:i])
+			last = i + 1
+		}
+	}
+	parts = append(parts, text[last:])
+	return parts
+}
+
+// yamlScalar decodes a single YAML scalar, including flow sequences
+// like [a, b, c] and quoted strings.
+func yamlScalar(text string) interface{} {
+	if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+		inner := strings.TrimSpace(text[1 : len(text)-1])
+		if inner == "" {
+			return []interface{}{}
+		}
+		parts := yamlSplitCommas(inner)
+		seq := make([]interface{}, len(parts))
+		for i, part := range parts {
+			seq[i] = yamlScalar(strings.TrimSpace(part))
+		}
+		return seq
+	}
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		return text[1 : len(text)-1]
+	}
+	switch text {
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case "null", "~", "":
+		return nil
+	}
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+	return text
+}
+
+// parseTOML parses the practical subset of TOML documented on
+// TOMLProvider: key = value pairs and [section] / [section.sub] tables.
+func parseTOML(source string) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	table := root
+	for _, raw := range strings.Split(source, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[[") && strings.HasSuffix(line, "]]") {
+			return nil, fmt.Errorf("envconf: array-of-tables %q is not supported", line)
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			path := strings.Split(strings.TrimSpace(line[1:len(line)-1]), ".")
+			table = root
+			for _, part := range path {
+				part = strings.TrimSpace(part)
+				next, ok := table[part].(map[string]interface{})
+				if !ok {
+					next = make(map[string]interface{})
+					table[part] = next
+				}
+				table = next
+			}
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("envconf: expected 'key = value', got %q", line)
+		}
+		key := strings.TrimSpace(line[:eq])
+		table[key] = yamlScalar(strings.TrimSpace(line[eq+1:]))
+	}
+	return root, nil
+}
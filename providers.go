@@ -0,0 +1,266 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package envconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A Provider supplies configuration values from a single source. Load
+// returns the values it contributes as a flat map of environment-style
+// keys, already prefixed and separated the same way Environment expects,
+// so the result of several Providers can be merged directly into an
+// Environment.
+type Provider interface {
+	Load(prefix, sep string) (map[string]string, error)
+}
+
+// A Loader merges configuration from an ordered list of Providers into a
+// single Environment. Providers later in the list override values set by
+// earlier ones, so a typical order is file-based defaults followed by an
+// EnvProvider so the process environment always wins.
+type Loader struct {
+	Providers []Provider
+}
+
+// Merge loads every provider in order and combines the results into an
+// Environment suitable for Decode or DecodeStrict. prefix and sep are
+// forwarded to each Provider so nested configuration is flattened into
+// the same PREFIX_SEP_... keyspace used by env decoding.
+func (l Loader) Merge(prefix, sep string) (Environment, error) {
+	env := make(Environment)
+	for _, provider := range l.Providers {
+		values, err := provider.Load(prefix, sep)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			env[key] = value
+		}
+	}
+	return env, nil
+}
+
+// EnvProvider supplies configuration from the process environment, or
+// from Environ if set. It is typically placed last in a Loader's
+// Providers so the environment overrides file-based configuration.
+type EnvProvider struct {
+	// Environ holds raw "KEY=VALUE" entries, as returned by os.Environ.
+	// If nil, os.Environ is used.
+	Environ []string
+}
+
+// Load implements Provider. prefix and sep are ignored: env entries are
+// already in their final PREFIX_SEP_... form.
+func (p EnvProvider) Load(prefix, sep string) (map[string]string, error) {
+	environ := p.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+	return map[string]string(New(environ)), nil
+}
+
+// DotEnvProvider loads configuration from a dotenv-style file: one
+// KEY=VALUE assignment per line, blank lines and '#' comments ignored,
+// and an optional leading "export ". Values may be wrapped in single
+// quotes (taken literally) or double quotes (supporting \n, \t and \"
+// escapes); unquoted and double-quoted values have ${VAR} references
+// expanded against variables defined earlier in the file, falling back
+// to the process environment.
+type DotEnvProvider struct {
+	Path string
+}
+
+// FilePath implements filePather, allowing a Watcher to poll this
+// provider's underlying file for changes.
+func (p DotEnvProvider) FilePath() string { return p.Path }
+
+// Load implements Provider. prefix and sep are ignored: keys are returned
+// as written in the file, upper-cased.
+func (p DotEnvProvider) Load(prefix, sep string) (map[string]string, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	defined := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToUpper(strings.TrimSpace(parts[0]))
+		defined[key] = parseDotEnvValue(strings.TrimSpace(parts[1]), defined)
+	}
+	return defined, nil
+}
+
+// parseDotEnvValue strips quoting from a dotenv value and, for unquoted
+// and double-quoted values, expands ${VAR} references against defined
+// (variables seen earlier in the file) and the process environment.
+func parseDotEnvValue(value string, defined map[string]string) string {
+	expand := func(s string) string {
+		return os.Expand(s, func(name string) string {
+			if v, ok := defined[strings.ToUpper(name)]; ok {
+				return v
+			}
+			return os.Getenv(name)
+		})
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1]
+	}
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		unquoted := value[1 : len(value)-1]
+		unquoted = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(unquoted)
+		return expand(unquoted)
+	}
+	return expand(value)
+}
+
+// JSONProvider loads configuration from a JSON object file. Nested
+// objects are flattened into the same PREFIX_SEP_... keyspace used by
+// env decoding, e.g. {"storage":{"max_conns":500}} becomes
+// STORAGE_MAX_CONNS=500 under the given prefix.
+type JSONProvider struct {
+	Path string
+}
+
+// FilePath implements filePather, allowing a Watcher to poll this
+// provider's underlying file for changes.
+func (p JSONProvider) FilePath() string { return p.Path }
+
+// Load implements Provider.
+func (p JSONProvider) Load(prefix, sep string) (map[string]string, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	flattenValues(prefix, sep, parsed, result)
+	return result, nil
+}
+
+// YAMLProvider loads configuration from a YAML file. It supports the
+// practical subset of YAML used for configuration: nested mappings via
+// two-space indentation, block and flow sequences, and plain, single-
+// and double-quoted scalars. It does not implement the full YAML spec
+// (anchors, multi-document streams, and flow mappings are not
+// supported).
+type YAMLProvider struct {
+	Path string
+}
+
+// FilePath implements filePather, allowing a Watcher to poll this
+// provider's underlying file for changes.
+func (p YAMLProvider) FilePath() string { return p.Path }
+
+// Load implements Provider.
+func (p YAMLProvider) Load(prefix, sep string) (map[string]string, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseYAML(string(data))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	flattenValues(prefix, sep, parsed, result)
+	return result, nil
+}
+
+// TOMLProvider loads configuration from a TOML file. It supports the
+// practical subset of TOML used for configuration: key = value pairs,
+// [section] and [section.sub] tables, and string, integer, float,
+// Boolean, and array values. It does not implement the full TOML spec
+// (inline tables and arrays of tables are not supported).
+type TOMLProvider struct {
+	Path string
+}
+
+// FilePath implements filePather, allowing a Watcher to poll this
+// provider's underlying file for changes.
+func (p TOMLProvider) FilePath() string { return p.Path }
+
+// Load implements Provider.
+func (p TOMLProvider) Load(prefix, sep string) (map[string]string, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseTOML(string(data))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	flattenValues(prefix, sep, parsed, result)
+	return result, nil
+}
+
+// flattenValues walks a (possibly nested) map decoded from a structured
+// config file and writes it into out using the same PREFIX_SEP_...
+// keyspace decodeField expects: nested maps recurse with their key
+// appended to the path, and scalars/arrays are stringified into the
+// comma-separated form decodeLiteral/decodeSlice already understand.
+func flattenValues(prefix, sep string, data map[string]interface{}, out map[string]string) {
+	for key, value := range data {
+		path := strings.ToUpper(key)
+		if prefix != "" {
+			path = prefix + sep + path
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenValues(path, sep, v, out)
+		case []interface{}:
+			elements := make([]string, len(v))
+			for i, e := range v {
+				elements[i] = escapeListElement(stringifyValue(e))
+			}
+			out[path] = strings.Join(elements, ",")
+		default:
+			out[path] = stringifyValue(value)
+		}
+	}
+}
+
+// stringifyValue renders a decoded JSON/YAML/TOML scalar back into the
+// string form decodeLiteral expects.
+func stringifyValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeListElement escapes backslashes and commas in s so it round-trips
+// through splitList when joined with other elements.
+func escapeListElement(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `,`, `\,`)
+	return s
+}
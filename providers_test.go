@@ -0,0 +1,153 @@
+package envconf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type loaderConfig struct {
+	Addr    string
+	Storage struct {
+		MaxConns int      `env:"max_conns"`
+		Hosts    []string `env:"hosts"`
+	}
+}
+
+func writeTemp(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing %s: %s", path, err)
+	}
+	return path
+}
+
+func TestJSONProvider(t *testing.T) {
+	path := writeTemp(t, "config.json", `{
+		"addr": ":8080",
+		"storage": {"max_conns": 500, "hosts": ["a", "b"]}
+	}`)
+	values, err := (JSONProvider{Path: path}).Load("APP", "_")
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	expected := map[string]string{
+		"APP_ADDR":              ":8080",
+		"APP_STORAGE_MAX_CONNS": "500",
+		"APP_STORAGE_HOSTS":     "a,b",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("got %#v; want %#v", values, expected)
+	}
+}
+
+func TestYAMLProvider(t *testing.T) {
+	path := writeTemp(t, "config.yaml", "addr: \":8080\"\nstorage:\n  max_conns: 500\n  hosts:\n    - a\n    - b\n")
+	values, err := (YAMLProvider{Path: path}).Load("APP", "_")
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	expected := map[string]string{
+		"APP_ADDR":              ":8080",
+		"APP_STORAGE_MAX_CONNS": "500",
+		"APP_STORAGE_HOSTS":     "a,b",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("got %#v; want %#v", values, expected)
+	}
+}
+
+func TestYAMLProviderQuotedFlowSequence(t *testing.T) {
+	path := writeTemp(t, "config.yaml", `tags: ["a,b", c]`+"\n")
+	values, err := (YAMLProvider{Path: path}).Load("APP", "_")
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if values["APP_TAGS"] != `a\,b,c` {
+		t.Errorf("got APP_TAGS=%q; want %q", values["APP_TAGS"], `a\,b,c`)
+	}
+}
+
+func TestTOMLProviderArrayOfTablesUnsupported(t *testing.T) {
+	path := writeTemp(t, "config.toml", "[[servers]]\nhost = \"a\"\n")
+	if _, err := (TOMLProvider{Path: path}).Load("APP", "_"); err == nil {
+		t.Error("expected error loading array-of-tables TOML, got nil")
+	}
+}
+
+func TestTOMLProvider(t *testing.T) {
+	path := writeTemp(t, "config.toml", "addr = \":8080\"\n\n[storage]\nmax_conns = 500\nhosts = [\"a\", \"b\"]\n")
+	values, err := (TOMLProvider{Path: path}).Load("APP", "_")
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	expected := map[string]string{
+		"APP_ADDR":              ":8080",
+		"APP_STORAGE_MAX_CONNS": "500",
+		"APP_STORAGE_HOSTS":     "a,b",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("got %#v; want %#v", values, expected)
+	}
+}
+
+func TestDotEnvProvider(t *testing.T) {
+	path := writeTemp(t, ".env", "# comment\nexport ADDR=:8080\nGREETING=\"hello ${ADDR}\"\nLITERAL='raw ${ADDR}'\n")
+	values, err := (DotEnvProvider{Path: path}).Load("", "_")
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if values["ADDR"] != ":8080" {
+		t.Errorf("got ADDR=%q; want %q", values["ADDR"], ":8080")
+	}
+	if values["GREETING"] != "hello :8080" {
+		t.Errorf("got GREETING=%q; want %q", values["GREETING"], "hello :8080")
+	}
+	if values["LITERAL"] != "raw ${ADDR}" {
+		t.Errorf("got LITERAL=%q; want %q", values["LITERAL"], "raw ${ADDR}")
+	}
+}
+
+func TestJSONProviderListRoundTrip(t *testing.T) {
+	path := writeTemp(t, "config.json", `{"paths": ["C:\\path", "a,b", "plain"]}`)
+	values, err := (JSONProvider{Path: path}).Load("APP", "_")
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	var cfg struct {
+		Paths []string
+	}
+	if err := Environment(values).Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	expected := []string{`C:\path`, "a,b", "plain"}
+	if !reflect.DeepEqual(cfg.Paths, expected) {
+		t.Errorf("got %#v; want %#v", cfg.Paths, expected)
+	}
+}
+
+func TestLoaderMerge(t *testing.T) {
+	jsonPath := writeTemp(t, "config.json", `{"addr": ":8080", "storage": {"max_conns": 500}}`)
+	loader := Loader{
+		Providers: []Provider{
+			JSONProvider{Path: jsonPath},
+			EnvProvider{Environ: []string{"APP_STORAGE_MAX_CONNS=999"}},
+		},
+	}
+	env, err := loader.Merge("APP", "_")
+	if err != nil {
+		t.Fatalf("Merge: %s", err)
+	}
+	var cfg loaderConfig
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	if cfg.Addr != ":8080" {
+		t.Errorf("got Addr=%q; want %q", cfg.Addr, ":8080")
+	}
+	if cfg.Storage.MaxConns != 999 {
+		t.Errorf("got MaxConns=%d; want later provider's override of 999", cfg.Storage.MaxConns)
+	}
+}
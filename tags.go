@@ -0,0 +1,52 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package envconf
+
+import (
+	"reflect"
+	"strings"
+)
+
+// envTag is the parsed form of a field's `env` struct tag:
+// env:"NAME,option,option=value,...". Supported options are "default=...",
+// "required", "expand", and "file"; see decodeField.
+type envTag struct {
+	name         string
+	skip         bool
+	required     bool
+	expand       bool
+	file         bool
+	hasDefault   bool
+	defaultValue string
+}
+
+// parseEnvTag parses field's env tag into its name and options. A bare
+// env:"-" tag opts the field out of decoding entirely.
+func parseEnvTag(field reflect.StructField) envTag {
+	raw := field.Tag.Get("env")
+	if raw == "-" {
+		return envTag{skip: true}
+	}
+	parts := strings.Split(raw, ",")
+	tag := envTag{name: strings.TrimSpace(parts[0])}
+	if tag.name == "" {
+		tag.name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "required":
+			tag.required = true
+		case opt == "expand":
+			tag.expand = true
+		case opt == "file":
+			tag.file = true
+		case strings.HasPrefix(opt, "default="):
+			tag.hasDefault = true
+			tag.defaultValue = strings.TrimPrefix(opt, "default=")
+		}
+	}
+	return tag
+}
@@ -0,0 +1,99 @@
+package envconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type taggedConfig struct {
+	Port     int    `env:"port,default=8080"`
+	Name     string `env:"name,required"`
+	Greeting string `env:"greeting,expand"`
+	Secret   string `env:"secret,file"`
+}
+
+func TestDecodeDefault(t *testing.T) {
+	env := New([]string{"APP_NAME=svc"})
+	var cfg taggedConfig
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d; want default 8080", cfg.Port)
+	}
+}
+
+func TestDecodeRequiredMissing(t *testing.T) {
+	env := New([]string{"APP_PORT=9090"})
+	var cfg taggedConfig
+	err := env.Decode("APP", "_", &cfg)
+	if err == nil {
+		t.Fatal("Expected error for missing required field")
+	}
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("got %#v; want a single ValidationErrors entry", err)
+	}
+	if _, ok := errs[0].(*RequiredFieldError); !ok {
+		t.Errorf("got %#v; want *RequiredFieldError", errs[0])
+	}
+}
+
+func TestDecodeExpand(t *testing.T) {
+	env := New([]string{
+		"APP_NAME=svc",
+		"APP_USER=ren",
+		"APP_GREETING=hello ${APP_USER}",
+	})
+	var cfg taggedConfig
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	if cfg.Greeting != "hello ren" {
+		t.Errorf("Greeting = %q; want %q", cfg.Greeting, "hello ren")
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("hunter2\n"), 0o600); err != nil {
+		t.Fatalf("writing secret file: %s", err)
+	}
+	env := New([]string{
+		"APP_NAME=svc",
+		"APP_SECRET=" + path,
+	})
+	var cfg taggedConfig
+	if err := env.Decode("APP", "_", &cfg); err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+	if cfg.Secret != "hunter2" {
+		t.Errorf("Secret = %q; want %q", cfg.Secret, "hunter2")
+	}
+}
+
+type validatedConfig struct {
+	Port  int    `validate:"min=1,max=65535"`
+	Level string `validate:"oneof=debug|info|warn|error"`
+	Name  string `validate:"regexp=^[a-z]+$"`
+}
+
+func TestValidate(t *testing.T) {
+	cfg := &validatedConfig{Port: 70000, Level: "loud", Name: "Ren1"}
+	err := Validate(cfg)
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 3 {
+		t.Fatalf("got %#v; want 3 aggregated errors", err)
+	}
+}
+
+func TestValidatePasses(t *testing.T) {
+	cfg := &validatedConfig{Port: 443, Level: "info", Name: "stimpy"}
+	if err := Validate(cfg); err != nil {
+		t.Errorf("Unexpected validation error: %s", err)
+	}
+}
@@ -0,0 +1,175 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package envconf
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RequiredFieldError records that a field tagged env:"...,required" had no
+// corresponding environment variable set.
+type RequiredFieldError struct {
+	// Field is the fully-prefixed env variable name that was required but
+	// missing.
+	Field string
+}
+
+func (e *RequiredFieldError) Error() string {
+	return fmt.Sprintf("Required environment variable '%s' is not set", e.Field)
+}
+
+// ValidationErrors aggregates the failures found in a single pass rather
+// than stopping at the first one: every missing required field in a
+// Decode, or every failing field in a Validate. A Decode that fails to
+// parse a value returns that error alone instead.
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate walks v, which must be a non-nil pointer to a struct, checking
+// every field's `validate` struct tag and aggregating one error per failing
+// field (the first rule it fails) into a single ValidationErrors instead of
+// returning on the first failing field. Supported
+// rules, comma-separated within the tag: min=N and max=N (compared
+// numerically for numeric fields, by length for strings, slices, and
+// maps), oneof=a|b|c, and regexp=pattern.
+func Validate(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("envconf: Validate requires a non-nil pointer, got %T", v)
+	}
+	var errs ValidationErrors
+	validateValue("", value.Elem(), &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateValue recursively applies validateField to every exported field
+// of a struct value, descending into nested structs.
+func validateValue(path string, value reflect.Value, errs *ValidationErrors) {
+	typ := value.Type()
+	if typ.Kind() != reflect.Struct || isLiteralType(value) {
+		return
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		fieldValue := indirect(value.Field(i))
+		if rule := field.Tag.Get("validate"); rule != "" {
+			if err := validateField(fieldPath, rule, fieldValue); err != nil {
+				*errs = append(*errs, err)
+			}
+		}
+		if fieldValue.Type().Kind() == reflect.Struct && !isLiteralType(fieldValue) {
+			validateValue(fieldPath, fieldValue, errs)
+		}
+	}
+}
+
+// validateField applies every comma-separated rule in tag to value,
+// returning the first one that fails.
+func validateField(path, tag string, value reflect.Value) error {
+	for _, clause := range strings.Split(tag, ",") {
+		clause = strings.TrimSpace(clause)
+		switch {
+		case clause == "":
+			continue
+		case strings.HasPrefix(clause, "min="):
+			if err := validateBound(path, clause, "min=", value, func(n, limit float64) bool { return n < limit }); err != nil {
+				return err
+			}
+		case strings.HasPrefix(clause, "max="):
+			if err := validateBound(path, clause, "max=", value, func(n, limit float64) bool { return n > limit }); err != nil {
+				return err
+			}
+		case strings.HasPrefix(clause, "oneof="):
+			options := strings.Split(strings.TrimPrefix(clause, "oneof="), "|")
+			actual := fmt.Sprint(value.Interface())
+			if !containsString(options, actual) {
+				return fmt.Errorf("%s must be one of %s, got %q", path, strings.Join(options, ", "), actual)
+			}
+		case strings.HasPrefix(clause, "regexp="):
+			pattern := strings.TrimPrefix(clause, "regexp=")
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("envconf: invalid regexp rule on '%s': %s", path, err)
+			}
+			actual := fmt.Sprint(value.Interface())
+			if !re.MatchString(actual) {
+				return fmt.Errorf("%s must match %s, got %q", path, pattern, actual)
+			}
+		}
+	}
+	return nil
+}
+
+// validateBound checks value against a single min=/max= clause, comparing
+// numerically for numeric kinds and by length otherwise. fails reports
+// whether n violates limit (n < limit for min, n > limit for max).
+func validateBound(path, clause, prefix string, value reflect.Value, fails func(n, limit float64) bool) error {
+	limit, err := strconv.ParseFloat(strings.TrimPrefix(clause, prefix), 64)
+	if err != nil {
+		return fmt.Errorf("envconf: invalid %srule on '%s': %s", prefix, path, err)
+	}
+	if n, ok := numericValue(value); ok {
+		if fails(n, limit) {
+			return fmt.Errorf("%s must satisfy %s%v, got %v", path, prefix, limit, n)
+		}
+		return nil
+	}
+	if length, ok := lengthValue(value); ok {
+		if fails(float64(length), limit) {
+			return fmt.Errorf("%s must have length satisfying %s%v, got %d", path, prefix, limit, length)
+		}
+	}
+	return nil
+}
+
+func numericValue(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+func lengthValue(value reflect.Value) (int, bool) {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len(), true
+	}
+	return 0, false
+}
+
+func containsString(options []string, value string) bool {
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package envconf
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// filePather is implemented by Providers backed by a single file on
+// disk, letting a Watcher know which paths to poll for changes.
+type filePather interface {
+	FilePath() string
+}
+
+const (
+	// defaultPollInterval is how often a Watcher checks watched files
+	// for changes.
+	defaultPollInterval = time.Second
+
+	// defaultDebounce coalesces rapid successive writes (e.g. editors
+	// that write a temp file and rename it over the original) into a
+	// single reload.
+	defaultDebounce = 250 * time.Millisecond
+)
+
+// Watcher re-decodes a struct of type T from a Loader whenever one of the
+// Loader's file-backed Providers changes on disk, and notifies callbacks
+// registered with OnChange. The current decoded value is available at
+// any time via Get, which is safe to call concurrently with reloads.
+type Watcher[T any] struct {
+	loader Loader
+	prefix string
+	sep    string
+
+	// PollInterval is how often watched files are checked for changes.
+	// Zero means defaultPollInterval.
+	PollInterval time.Duration
+	// Debounce is how long to wait after a change is observed before
+	// reloading, to coalesce rapid successive writes. Zero means
+	// defaultDebounce.
+	Debounce time.Duration
+
+	current atomic.Value
+
+	mu        sync.Mutex
+	callbacks []func(old, new *T, diff []string)
+	stopOnce  sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewWatcher creates a Watcher that decodes prefix/sep-scoped
+// configuration from loader into a new value of the same type as v,
+// decoding once immediately so Get returns a usable snapshot before
+// Start is called. T is inferred from v, so NewWatcher(loader, "APP",
+// "_", &ServerConfig{}) returns a *Watcher[ServerConfig].
+func NewWatcher[T any](loader Loader, prefix, sep string, v *T) (*Watcher[T], error) {
+	w := &Watcher[T]{
+		loader: loader,
+		prefix: prefix,
+		sep:    sep,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	initial, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(initial)
+	return w, nil
+}
+
+// Get returns the most recently decoded configuration value.
+func (w *Watcher[T]) Get() *T {
+	return w.current.Load().(*T)
+}
+
+// OnChange registers fn to be called after a reload whose decoded value
+// differs from the previous one. diff lists the env keys of every field
+// whose decoded value changed.
+func (w *Watcher[T]) OnChange(fn func(old, new *T, diff []string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, fn)
+}
+
+// Start begins polling the Loader's file-backed Providers for changes in
+// a background goroutine, reloading and notifying callbacks when they
+// change. Start returns immediately; call Stop to end polling.
+func (w *Watcher[T]) Start() {
+	go w.run()
+}
+
+// Stop ends polling and waits for the background goroutine to exit.
+func (w *Watcher[T]) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+func (w *Watcher[T]) run() {
+	defer close(w.done)
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastMod := w.modTimes()
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			modTimes := w.modTimes()
+			if !sameModTimes(lastMod, modTimes) {
+				lastMod = modTimes
+				pending = time.After(w.debounce())
+			}
+		case <-pending:
+			pending = nil
+			w.reload()
+			lastMod = w.modTimes()
+		}
+	}
+}
+
+func (w *Watcher[T]) debounce() time.Duration {
+	if w.Debounce > 0 {
+		return w.Debounce
+	}
+	return defaultDebounce
+}
+
+// modTimes returns the modification time of every file-backed Provider's
+// underlying file, keyed by path.
+func (w *Watcher[T]) modTimes() map[string]time.Time {
+	times := make(map[string]time.Time)
+	for _, provider := range w.loader.Providers {
+		pather, ok := provider.(filePather)
+		if !ok {
+			continue
+		}
+		info, err := os.Stat(pather.FilePath())
+		if err != nil {
+			continue
+		}
+		times[pather.FilePath()] = info.ModTime()
+	}
+	return times
+}
+
+func sameModTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, modTime := range a {
+		if !b[path].Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// load decodes a fresh value of the Watcher's configured type from the
+// Loader.
+func (w *Watcher[T]) load() (*T, error) {
+	env, err := w.loader.Merge(w.prefix, w.sep)
+	if err != nil {
+		return nil, err
+	}
+	next := new(T)
+	if err := env.Decode(w.prefix, w.sep, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// reload decodes a fresh value and, if it differs from the current one,
+// stores it and notifies every registered callback.
+func (w *Watcher[T]) reload() {
+	next, err := w.load()
+	if err != nil {
+		return
+	}
+	old := w.current.Load().(*T)
+	diff := diffStruct(w.prefix, w.sep, reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem())
+	if len(diff) == 0 {
+		return
+	}
+	w.current.Store(next)
+
+	w.mu.Lock()
+	callbacks := append([]func(old, new *T, diff []string){}, w.callbacks...)
+	w.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(old, next, diff)
+	}
+}
+
+// diffStruct recursively compares two struct values of the same type,
+// returning the env keys of every leaf field whose value differs.
+func diffStruct(prefix, sep string, oldValue, newValue reflect.Value) []string {
+	var diff []string
+	typ := oldValue.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, ok := envFieldName(field)
+		if !ok {
+			continue
+		}
+		path := prefix + sep + name
+		if field.Anonymous && field.Tag.Get("env") == "" {
+			path = prefix
+		}
+		oldField, newField := oldValue.Field(i), newValue.Field(i)
+		if oldField.Kind() == reflect.Struct && !isLiteralTypeType(oldField.Type()) {
+			diff = append(diff, diffStruct(path, sep, oldField, newField)...)
+			continue
+		}
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			diff = append(diff, path)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,70 @@
+package envconf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type watchedConfig struct {
+	Addr     string
+	MaxConns int `env:"max_conns"`
+}
+
+func TestDiffStructUnexportedFields(t *testing.T) {
+	old := &StorageConfig{MaxConns: 1}
+	new := &StorageConfig{MaxConns: 2}
+	diff := diffStruct("APP", "_", reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem())
+	if len(diff) != 1 || diff[0] != "APP_max_conns" {
+		t.Errorf("diff = %v; want [APP_max_conns]", diff)
+	}
+}
+
+func TestWatcherReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"addr": ":8080", "max_conns": 10}`), 0o644); err != nil {
+		t.Fatalf("writing config: %s", err)
+	}
+
+	loader := Loader{Providers: []Provider{JSONProvider{Path: path}}}
+	watcher, err := NewWatcher(loader, "APP", "_", &watchedConfig{})
+	if err != nil {
+		t.Fatalf("NewWatcher: %s", err)
+	}
+	if got := watcher.Get(); got.MaxConns != 10 {
+		t.Fatalf("initial MaxConns = %d; want 10", got.MaxConns)
+	}
+
+	watcher.PollInterval = 10 * time.Millisecond
+	watcher.Debounce = 10 * time.Millisecond
+
+	changed := make(chan []string, 1)
+	watcher.OnChange(func(old, new *watchedConfig, diff []string) {
+		changed <- diff
+	})
+	watcher.Start()
+	defer watcher.Stop()
+
+	// Give the watcher time to establish its initial modtime baseline
+	// before the file changes, so the change is observed as a change.
+	time.Sleep(20 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(`{"addr": ":9090", "max_conns": 20}`), 0o644); err != nil {
+		t.Fatalf("rewriting config: %s", err)
+	}
+
+	select {
+	case diff := <-changed:
+		if len(diff) != 2 {
+			t.Errorf("diff = %v; want 2 changed fields", diff)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for OnChange")
+	}
+
+	got := watcher.Get()
+	if got.Addr != ":9090" || got.MaxConns != 20 {
+		t.Errorf("got %#v; want Addr=:9090 MaxConns=20", got)
+	}
+}